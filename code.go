@@ -0,0 +1,88 @@
+package errx
+
+import "sync"
+
+// ErrorCode is a small, comparable error classification that callers can
+// branch on without resorting to string matching, e.g. distinguishing
+// "not found" from other wrapped I/O errors.
+type ErrorCode string
+
+// NewCode creates a new error with a stack trace at the point which NewCode
+// was called, a message, and the given code.
+//
+//go:noinline
+func NewCode(code ErrorCode, message string) error {
+	e := newErr(message, 0).(*Error)
+	e.code = code
+	return e
+}
+
+// WrapCode wraps an existing error with a message and the given code. As with
+// Wrap, a stack trace is only captured if err is not already an *errx.Error.
+//
+//go:noinline
+func WrapCode(err error, code ErrorCode, message string) error {
+	e := wrapErr(err, message, 0).(*Error)
+	e.code = code
+	return e
+}
+
+// Code returns the first code found while walking err's chain, and whether one
+// was found at all.
+func Code(err error) (ErrorCode, bool) {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.code != "" {
+			return e.code, true
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return "", false
+}
+
+// HasCode reports whether err's chain contains the given code.
+func HasCode(err error, code ErrorCode) bool {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.code != "" && e.code == code {
+			return true
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return false
+}
+
+// Registry associates ErrorCodes with downstream, domain-specific values, for
+// example mapping errx codes to gRPC or HTTP status codes in one place rather
+// than scattering the mapping across callers. A Registry is safe for
+// concurrent use.
+type Registry struct {
+	mu sync.RWMutex
+	m  map[ErrorCode]interface{}
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{m: make(map[ErrorCode]interface{})}
+}
+
+// Register associates code with value, overwriting any previous association.
+func (r *Registry) Register(code ErrorCode, value interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.m[code] = value
+}
+
+// Lookup returns the value registered for code, and whether one was found.
+func (r *Registry) Lookup(code ErrorCode) (interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.m[code]
+	return v, ok
+}
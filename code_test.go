@@ -0,0 +1,70 @@
+package errx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1gm/errx"
+)
+
+func TestNewCode_WrapCode_Code_HasCode(t *testing.T) {
+	td := []struct {
+		name     string
+		err      error
+		wantCode errx.ErrorCode
+		wantOK   bool
+	}{
+		{"NewCode", errx.NewCode("not_found", "volume missing"), "not_found", true},
+		{"WrapCode", errx.WrapCode(errors.New("inner"), "internal", "wrap failed"), "internal", true},
+		{"no code", errx.New("plain"), "", false},
+	}
+
+	for _, test := range td {
+		t.Run(test.name, func(t *testing.T) {
+			code, ok := errx.Code(test.err)
+			if ok != test.wantOK {
+				t.Fatalf("expected ok to be %v but was %v", test.wantOK, ok)
+			}
+			if code != test.wantCode {
+				t.Fatalf("expected code to be %q but was %q", test.wantCode, code)
+			}
+			if got := errx.HasCode(test.err, test.wantCode); got != test.wantOK {
+				t.Fatalf("expected HasCode to be %v but was %v", test.wantOK, got)
+			}
+		})
+	}
+}
+
+func TestCode_WalksChain(t *testing.T) {
+	err := errx.Wrap(errx.NewCode("not_found", "volume missing"), "delete volume")
+
+	code, ok := errx.Code(err)
+	if !ok {
+		t.Fatal("expected code to be found through the chain")
+	}
+	if code != "not_found" {
+		t.Fatalf("expected code to be not_found but was %s", code)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := errx.NewRegistry()
+
+	if _, ok := r.Lookup("not_found"); ok {
+		t.Fatal("expected lookup on empty registry to fail")
+	}
+
+	r.Register("not_found", 404)
+	v, ok := r.Lookup("not_found")
+	if !ok {
+		t.Fatal("expected lookup to succeed after Register")
+	}
+	if v != 404 {
+		t.Fatalf("expected 404 but was %v", v)
+	}
+
+	r.Register("not_found", 410)
+	if v, _ := r.Lookup("not_found"); v != 410 {
+		t.Fatalf("expected Register to overwrite previous value, got %v", v)
+	}
+}
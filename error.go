@@ -59,10 +59,17 @@
 //
 //		outer
 //
+// Printing the JSON representation of an error, including any fields attached
+// with WithFields/With:
+//
+//		err := errx.With(errx.Wrap(errors.New("inner"), "outer"), "volumeID", "abc123")
+//		log.Printf("%j", err)
+//
 package errx
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -80,6 +87,10 @@ type Error struct {
 	Message string
 	// StackTrace is the StackTrace of the inner most error, hoisted up into this error.
 	StackTrace StackTrace
+	// fields holds structured key/value context attached via WithFields/With.
+	fields map[string]interface{}
+	// code holds the error code attached via NewCode/WrapCode, if any.
+	code ErrorCode
 }
 
 // Error returns an error string, including all inner errors, each separated by
@@ -102,6 +113,13 @@ func (e *Error) Format(f fmt.State, c rune) {
 		} else {
 			fmt.Fprint(f, e.error(0, ": ", true))
 		}
+	} else if c == 'j' {
+		b, err := json.Marshal(e)
+		if err != nil {
+			fmt.Fprintf(f, `{"error":%q}`, err.Error())
+			return
+		}
+		f.Write(b)
 	}
 }
 
@@ -111,6 +129,11 @@ func (e *Error) error(depth int, separator string, printStack bool) string {
 	b := new(bytes.Buffer)
 	if e.Message != "" {
 		pad(b, padding)
+		if e.code != "" {
+			b.WriteString("[")
+			b.WriteString(string(e.code))
+			b.WriteString("] ")
+		}
 		b.WriteString(e.Message)
 	}
 
@@ -151,40 +174,62 @@ func pad(b *bytes.Buffer, str string) {
 }
 
 // New creates a new error with a stack trace at the point which New was called,
-// a message, and a nil inner error.
-func New(message string) error {
-	return newErr(message)
+// a message, and a nil inner error. Opts can be used to adjust the caller-skip
+// depth, for example when New is called from inside a helper function; see
+// Skip.
+//
+//go:noinline
+func New(message string, opts ...Option) error {
+	return newErr(message, buildOptions(opts).skip)
 }
 
 // Errorf creates a new error with a stack trace at the point which Errorf was called,
-// a formatted message, and a nil inner error.
+// a formatted message, and a nil inner error. Errorf cannot take Options since it
+// already accepts variadic format arguments; use a Builder if the caller-skip depth
+// needs adjusting.
+//
+//go:noinline
 func Errorf(format string, args ...interface{}) error {
-	return newErr(fmt.Sprintf(format, args...))
+	return newErr(fmt.Sprintf(format, args...), 0)
 }
 
 // Wrap wraps an existing error with a message. If the inner error is an errx.Error, then
 // no stack trace is added, otherwise a stack trace is captured at the point which Wrap
-// was called.
-func Wrap(err error, message string) error {
-	return wrapErr(err, message)
+// was called. Opts can be used to adjust the caller-skip depth; see Skip.
+//
+//go:noinline
+func Wrap(err error, message string, opts ...Option) error {
+	return wrapErr(err, message, buildOptions(opts).skip)
 }
 
 // Wrapf wraps an existing error with a formatted message. If the inner error is an
 // errx.Error, then no stack trace is added, otherwise a stack trace is captured at
-// the point which Wrapf was called.
+// the point which Wrapf was called. Wrapf cannot take Options since it already
+// accepts variadic format arguments; use a Builder if the caller-skip depth needs
+// adjusting.
+//
+//go:noinline
 func Wrapf(err error, format string, args ...interface{}) error {
-	return wrapErr(err, fmt.Sprintf(format, args...))
+	return wrapErr(err, fmt.Sprintf(format, args...), 0)
 }
 
-func newErr(message string) error {
+// newErr and wrapErr are marked go:noinline, along with getStack and every
+// exported constructor that calls them, so that callerSkipLevel counts a fixed
+// number of physical stack frames regardless of the compiler's inlining
+// decisions. Without this, enabling/disabling inlining for any of these
+// functions silently shifts which frame gets reported as the call site.
+
+//go:noinline
+func newErr(message string, skip int) error {
 	return &Error{
 		Inner:      nil,
 		Message:    message,
-		StackTrace: getStack(),
+		StackTrace: getStack(skip),
 	}
 }
 
-func wrapErr(err error, message string) error {
+//go:noinline
+func wrapErr(err error, message string, skip int) error {
 	e := &Error{Message: message}
 
 	if inner, ok := err.(*Error); ok {
@@ -194,6 +239,6 @@ func wrapErr(err error, message string) error {
 	}
 
 	e.Inner = err
-	e.StackTrace = getStack()
+	e.StackTrace = getStack(skip)
 	return e
 }
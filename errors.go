@@ -0,0 +1,99 @@
+package errx
+
+import (
+	"errors"
+	"sync"
+)
+
+// sentinel is a distinguishable error value. Unlike a plain *Error, a sentinel
+// is never copied: Wrap only copies the *Error it is directly wrapping, so a
+// sentinel stored further down the Inner chain keeps its identity no matter
+// how many times the chain around it is wrapped. This lets errors.Is find a
+// sentinel through arbitrarily many layers of errx.Wrap.
+type sentinel struct{ message string }
+
+func (s *sentinel) Error() string { return s.message }
+
+// Sentinel creates a new sentinel error for use with errors.Is. Call it once,
+// typically at package scope, and compare against the result later:
+//
+//		var ErrNotFound = errx.Sentinel("not found")
+//
+//		func do() error {
+//			return errx.Wrap(ErrNotFound, "delete volume")
+//		}
+//
+//		if errors.Is(do(), ErrNotFound) { ... }
+func Sentinel(message string) error {
+	return &sentinel{message: message}
+}
+
+var sentinelRegistry = struct {
+	mu sync.RWMutex
+	m  map[string]error
+}{m: make(map[string]error)}
+
+// RegisterSentinel stores err under name so it can be recovered later with
+// LookupSentinel, for sentinels that need to be found by name instead of an
+// importable package-level variable, e.g. across a plugin boundary. It is
+// safe for concurrent use.
+func RegisterSentinel(name string, err error) {
+	sentinelRegistry.mu.Lock()
+	defer sentinelRegistry.mu.Unlock()
+	sentinelRegistry.m[name] = err
+}
+
+// LookupSentinel returns the error registered under name via RegisterSentinel,
+// and whether one was found.
+func LookupSentinel(name string) (error, bool) {
+	sentinelRegistry.mu.RLock()
+	defer sentinelRegistry.mu.RUnlock()
+	err, ok := sentinelRegistry.m[name]
+	return err, ok
+}
+
+// Is reports whether e matches target, and is consulted by errors.Is for
+// every *Error in the chain. A *sentinel created by Sentinel matches by
+// identity, which survives being wrapped because Wrap only ever copies the
+// *Error it wraps, never the sentinel itself.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*sentinel)
+	if !ok {
+		return false
+	}
+	s, ok := e.Inner.(*sentinel)
+	return ok && s == t
+}
+
+// As attempts to find an error in e's chain that matches target, which must
+// be a non-nil pointer, and if so, sets target to that error value and
+// returns true. It exists so *Error explicitly satisfies the interface
+// errors.As looks for; errors.As already walks errx chains correctly via
+// Unwrap without it, so in practice this just defers to errors.As on Inner.
+func (e *Error) As(target interface{}) bool {
+	return errors.As(e.Inner, target)
+}
+
+// Unwrap returns the inner error, or nil if there is none. Implementing Unwrap
+// lets errx chains participate in errors.Is, errors.As, and errors.Unwrap from
+// the standard library.
+func (e *Error) Unwrap() error {
+	return e.Inner
+}
+
+// Cause returns the root cause of err by repeatedly unwrapping it, in the style
+// of the pkg/errors Cause function. If err does not implement Unwrap() error,
+// err itself is returned.
+func Cause(err error) error {
+	for {
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return err
+		}
+		next := u.Unwrap()
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
@@ -0,0 +1,86 @@
+package errx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/1gm/errx"
+)
+
+func TestSentinel_Is(t *testing.T) {
+	notFound := errx.Sentinel("not found")
+	other := errx.Sentinel("other")
+
+	td := []struct {
+		err      error
+		expected bool
+	}{
+		{errx.Wrap(notFound, "delete volume"), true},
+		{errx.Wrap(errx.Wrap(notFound, "delete volume"), "outer"), true},
+		{errx.Wrap(other, "delete volume"), false},
+		{errx.New("unrelated"), false},
+	}
+
+	for i, test := range td {
+		if got := errors.Is(test.err, notFound); got != test.expected {
+			t.Fatalf("[%d] expected errors.Is to be %v but was %v", i, test.expected, got)
+		}
+	}
+}
+
+func TestRegisterSentinel_LookupSentinel(t *testing.T) {
+	want := errx.Sentinel("registered sentinel")
+	errx.RegisterSentinel("test-sentinel", want)
+
+	got, ok := errx.LookupSentinel("test-sentinel")
+	if !ok {
+		t.Fatal("expected sentinel to be found")
+	}
+	if got != want {
+		t.Fatalf("expected %v but was %v", want, got)
+	}
+
+	if _, ok := errx.LookupSentinel("does-not-exist"); ok {
+		t.Fatal("expected sentinel to not be found")
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	inner := errors.New("inner")
+	err := errx.Wrap(inner, "outer")
+
+	if got := errors.Unwrap(err); got != inner {
+		t.Fatalf("expected %v but was %v", inner, got)
+	}
+}
+
+func TestAs(t *testing.T) {
+	var pathErr *myPathError
+	inner := &myPathError{path: "/tmp/foo"}
+	err := errx.Wrap(errx.Wrap(inner, "middle"), "outer")
+
+	if !errors.As(err, &pathErr) {
+		t.Fatal("expected errors.As to find the inner error")
+	}
+	if pathErr.path != "/tmp/foo" {
+		t.Fatalf("expected path to be /tmp/foo but was %s", pathErr.path)
+	}
+}
+
+type myPathError struct{ path string }
+
+func (e *myPathError) Error() string { return "path error: " + e.path }
+
+func TestCause(t *testing.T) {
+	root := errors.New("root cause")
+	err := errx.Wrap(errx.Wrap(root, "middle"), "outer")
+
+	if got := errx.Cause(err); got != root {
+		t.Fatalf("expected %v but was %v", root, got)
+	}
+
+	plain := errors.New("plain")
+	if got := errx.Cause(plain); got != plain {
+		t.Fatalf("expected Cause to return err itself when Unwrap is unimplemented, got %v", got)
+	}
+}
@@ -0,0 +1,132 @@
+package errx
+
+import "encoding/json"
+
+// WithFields attaches structured key/value context to err without flattening
+// it into the error message, so it can be recovered later with Fields or
+// emitted via MarshalJSON/the %j format verb. If err is already an *errx.Error,
+// a copy of it carries the merged fields, the same as wrapErr copies rather
+// than mutates the *Error it wraps; otherwise err is wrapped first, capturing
+// a stack trace at the point WithFields was called, the same as
+// Wrap(err, ""). err itself is never modified, so it remains safe to reuse
+// as a shared/package-level sentinel.
+//
+//go:noinline
+func WithFields(err error, fields map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var e Error
+	if inner, ok := err.(*Error); ok {
+		e = *inner
+	} else {
+		e = *(wrapErr(err, "", 0).(*Error))
+	}
+	return mergeFields(e, fields)
+}
+
+// With attaches a single key/value pair to err. See WithFields.
+//
+//go:noinline
+func With(err error, key string, value interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var e Error
+	if inner, ok := err.(*Error); ok {
+		e = *inner
+	} else {
+		e = *(wrapErr(err, "", 0).(*Error))
+	}
+	return mergeFields(e, map[string]interface{}{key: value})
+}
+
+// mergeFields returns a new *Error, copied from e, whose fields are the union
+// of e's existing fields and fields, with fields taking precedence on
+// duplicate keys.
+func mergeFields(e Error, fields map[string]interface{}) *Error {
+	merged := make(map[string]interface{}, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	e.fields = merged
+	return &e
+}
+
+// Fields returns the fields attached anywhere in err's chain, merged together
+// with fields on outer errors taking precedence over duplicate keys set
+// further down the chain. It returns nil if no fields were attached anywhere
+// in the chain.
+func Fields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			for k, v := range e.fields {
+				if fields == nil {
+					fields = make(map[string]interface{})
+				}
+				if _, exists := fields[k]; !exists {
+					fields[k] = v
+				}
+			}
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return fields
+}
+
+// MarshalJSON implements json.Marshaler, producing
+//
+//	{"message":"...","code":"...","fields":{...},"cause":...,"stack":[{"func":"...","file":"...","line":0}]}
+//
+// code, fields, cause and stack are omitted when empty. cause is the inner error, marshaled recursively if it is itself an
+// *errx.Error, or its Error() string otherwise. This lets errx errors be
+// dropped directly into JSON log output.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type stackFrame struct {
+		Func string `json:"func"`
+		File string `json:"file"`
+		Line int    `json:"line"`
+	}
+
+	aux := struct {
+		Message string                 `json:"message"`
+		Code    ErrorCode              `json:"code,omitempty"`
+		Fields  map[string]interface{} `json:"fields,omitempty"`
+		Cause   json.RawMessage        `json:"cause,omitempty"`
+		Stack   []stackFrame           `json:"stack,omitempty"`
+	}{
+		Message: e.Message,
+		Code:    e.code,
+		Fields:  e.fields,
+	}
+
+	if e.Inner != nil {
+		var (
+			b   []byte
+			err error
+		)
+		if inner, ok := e.Inner.(*Error); ok {
+			b, err = inner.MarshalJSON()
+		} else {
+			b, err = json.Marshal(e.Inner.Error())
+		}
+		if err != nil {
+			return nil, err
+		}
+		aux.Cause = b
+	}
+
+	for _, f := range e.StackTrace {
+		aux.Stack = append(aux.Stack, stackFrame{Func: f.FunctionName, File: f.TrimmedFileName, Line: f.Line})
+	}
+
+	return json.Marshal(aux)
+}
@@ -0,0 +1,129 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/1gm/errx"
+)
+
+func TestWithFields_DoesNotMutateShared(t *testing.T) {
+	shared := errx.New("shared sentinel")
+
+	a := errx.With(shared, "op", "A")
+	b := errx.With(shared, "op", "B")
+
+	if a == b {
+		t.Fatal("expected With to return distinct errors, not share identity")
+	}
+	if got := errx.Fields(a)["op"]; got != "A" {
+		t.Fatalf("expected a's op field to remain A but was %v", got)
+	}
+	if got := errx.Fields(b)["op"]; got != "B" {
+		t.Fatalf("expected b's op field to be B but was %v", got)
+	}
+	if fields := errx.Fields(shared); fields != nil {
+		t.Fatalf("expected shared to remain untouched but had fields %v", fields)
+	}
+}
+
+func TestWithFields_CapturesStackTrace(t *testing.T) {
+	err := errx.WithFields(errors.New("boom"), map[string]interface{}{"a": 1})
+
+	e, ok := err.(*errx.Error)
+	if !ok {
+		t.Fatalf("expected *errx.Error but was %T", err)
+	}
+	if e.StackTrace == nil {
+		t.Fatal("expected WithFields to capture a stack trace for a plain error, same as Wrap")
+	}
+}
+
+func TestWith_CapturesStackTrace(t *testing.T) {
+	err := errx.With(errors.New("boom"), "a", 1)
+
+	e, ok := err.(*errx.Error)
+	if !ok {
+		t.Fatalf("expected *errx.Error but was %T", err)
+	}
+	if e.StackTrace == nil {
+		t.Fatal("expected With to capture a stack trace for a plain error, same as Wrap")
+	}
+}
+
+func TestWithFields_Merge(t *testing.T) {
+	err := errx.WithFields(errors.New("boom"), map[string]interface{}{"a": 1})
+	err = errx.With(err, "b", 2)
+
+	want := map[string]interface{}{"a": 1, "b": 2}
+	if got := errx.Fields(err); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected fields %v but was %v", want, got)
+	}
+}
+
+func TestFields_OuterTakesPrecedence(t *testing.T) {
+	inner := errx.With(errx.New("inner"), "k", "inner-value")
+	outer := errx.With(errx.Wrap(inner, "outer"), "k", "outer-value")
+
+	fields := errx.Fields(outer)
+	if fields["k"] != "outer-value" {
+		t.Fatalf("expected outer field to win but got %v", fields["k"])
+	}
+}
+
+func TestFields_Nil(t *testing.T) {
+	if fields := errx.Fields(errx.New("no fields")); fields != nil {
+		t.Fatalf("expected nil fields but got %v", fields)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	err := errx.WithFields(errx.Wrap(errors.New("inner"), "outer"), map[string]interface{}{"volumeID": "abc123"})
+
+	b, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("unexpected error marshaling: %v", marshalErr)
+	}
+
+	var decoded struct {
+		Message string                 `json:"message"`
+		Fields  map[string]interface{} `json:"fields"`
+		Cause   json.RawMessage        `json:"cause"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+
+	if decoded.Message != "outer" {
+		t.Fatalf("expected message to be outer but was %s", decoded.Message)
+	}
+	if decoded.Fields["volumeID"] != "abc123" {
+		t.Fatalf("expected volumeID field but got %v", decoded.Fields)
+	}
+
+	var cause string
+	if err := json.Unmarshal(decoded.Cause, &cause); err != nil {
+		t.Fatalf("unexpected error unmarshaling cause: %v", err)
+	}
+	if cause != "inner" {
+		t.Fatalf("expected cause to be inner but was %s", cause)
+	}
+}
+
+func Test_JFormatVerb(t *testing.T) {
+	err := errx.Wrap(errors.New("inner"), "outer")
+	s := fmt.Sprintf("%j", err)
+
+	var decoded struct {
+		Message string `json:"message"`
+	}
+	if jsonErr := json.Unmarshal([]byte(s), &decoded); jsonErr != nil {
+		t.Fatalf("expected %%j output to be valid JSON, got error %v for %s", jsonErr, s)
+	}
+	if decoded.Message != "outer" {
+		t.Fatalf("expected message to be outer but was %s", decoded.Message)
+	}
+}
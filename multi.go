@@ -0,0 +1,162 @@
+package errx
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MultiError aggregates multiple errors into a single error value, in the
+// style of Go's errors.Join. It implements Unwrap() []error so errors.Is and
+// errors.As check each constituent error in turn.
+type MultiError struct {
+	errs []error
+}
+
+// Join returns an error that wraps every non-nil error in errs. If no non-nil
+// errors are given, Join returns nil. If exactly one non-nil error is given,
+// Join returns that error directly rather than allocating a MultiError.
+func Join(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return &MultiError{errs: nonNil}
+	}
+}
+
+// Append adds errs to dst, returning a single error, so that callers can
+// accumulate errors in a loop without importing a separate multierror
+// package:
+//
+//		var err error
+//		for _, item := range items {
+//			if e := process(item); e != nil {
+//				err = errx.Append(err, e)
+//			}
+//		}
+//		return err
+//
+// If dst is nil and only one non-nil error remains, that error is returned
+// directly rather than allocating a MultiError.
+func Append(dst error, errs ...error) error {
+	if m, ok := dst.(*MultiError); ok {
+		return Join(append(append([]error{}, m.errs...), errs...)...)
+	}
+	if dst == nil {
+		return Join(errs...)
+	}
+	return Join(append([]error{dst}, errs...)...)
+}
+
+// Error returns each constituent error's message, separated by "; ".
+func (m *MultiError) Error() string {
+	b := new(bytes.Buffer)
+	for i, err := range m.errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the wrapped errors, allowing errors.Is and errors.As to check
+// each of them.
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// Format implements fmt.Formatter using the same -v/v convention as *Error:
+// "%-v" prints each constituent's message on its own numbered line; "%v"
+// additionally prints each constituent's stack trace, deduplicating frames
+// that are identical across every sibling (i.e. the common caller above where
+// the siblings' individual errors originated) so the output stays readable.
+func (m *MultiError) Format(f fmt.State, c rune) {
+	if c != 'v' && c != 's' {
+		return
+	}
+
+	if f.Flag('-') {
+		for i, err := range m.errs {
+			if i > 0 {
+				fmt.Fprint(f, "\n")
+			}
+			fmt.Fprintf(f, "%d: %-v", i+1, err)
+		}
+		return
+	}
+
+	messages := make([]string, len(m.errs))
+	frames := make([][]string, len(m.errs))
+	for i, err := range m.errs {
+		messages[i] = err.Error()
+		if st, ok := err.(Stacktracer); ok {
+			for _, fr := range st.Stacktrace() {
+				frames[i] = append(frames[i], fmt.Sprintf("%v", fr))
+			}
+		}
+	}
+
+	common := commonTailFrames(frames)
+	for i := range m.errs {
+		if i > 0 {
+			fmt.Fprint(f, "\n")
+		}
+		fmt.Fprintf(f, "%d: %s", i+1, messages[i])
+		unique := len(frames[i]) - len(common)
+		if unique < 0 {
+			unique = 0
+		}
+		for _, line := range frames[i][:unique] {
+			fmt.Fprintf(f, "\n    at %s", line)
+		}
+	}
+	if len(common) > 0 {
+		fmt.Fprint(f, "\n  common frames:")
+		for _, line := range common {
+			fmt.Fprintf(f, "\n    at %s", line)
+		}
+	}
+}
+
+// commonTailFrames returns the longest common suffix shared by every
+// non-empty frame slice in all, or nil if there is no such common suffix or
+// fewer than two siblings have frames to compare.
+func commonTailFrames(all [][]string) []string {
+	var withFrames [][]string
+	for _, f := range all {
+		if len(f) > 0 {
+			withFrames = append(withFrames, f)
+		}
+	}
+	if len(withFrames) < 2 {
+		return nil
+	}
+
+	shortest := len(withFrames[0])
+	for _, f := range withFrames[1:] {
+		if len(f) < shortest {
+			shortest = len(f)
+		}
+	}
+
+	var common []string
+	for i := 1; i <= shortest; i++ {
+		candidate := withFrames[0][len(withFrames[0])-i]
+		for _, f := range withFrames[1:] {
+			if f[len(f)-i] != candidate {
+				return common
+			}
+		}
+		common = append([]string{candidate}, common...)
+	}
+	return common
+}
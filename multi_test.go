@@ -0,0 +1,74 @@
+package errx_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/1gm/errx"
+)
+
+func TestJoin(t *testing.T) {
+	if got := errx.Join(); got != nil {
+		t.Fatalf("expected Join() to be nil but was %v", got)
+	}
+	if got := errx.Join(nil, nil); got != nil {
+		t.Fatalf("expected Join(nil, nil) to be nil but was %v", got)
+	}
+
+	single := errors.New("only one")
+	if got := errx.Join(nil, single); got != single {
+		t.Fatalf("expected Join to return the lone error directly, got %v", got)
+	}
+
+	a, b := errors.New("a"), errors.New("b")
+	joined := errx.Join(a, b)
+	if joined.Error() != "a; b" {
+		t.Fatalf("expected combined message 'a; b' but was %s", joined.Error())
+	}
+	if !errors.Is(joined, a) || !errors.Is(joined, b) {
+		t.Fatal("expected errors.Is to find both constituent errors")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	var err error
+	err = errx.Append(err, errors.New("a"))
+	if err.Error() != "a" {
+		t.Fatalf("expected a single error to be returned directly, got %s", err.Error())
+	}
+
+	err = errx.Append(err, errors.New("b"))
+	if err.Error() != "a; b" {
+		t.Fatalf("expected 'a; b' but was %s", err.Error())
+	}
+
+	err = errx.Append(err, errors.New("c"))
+	if err.Error() != "a; b; c" {
+		t.Fatalf("expected 'a; b; c' but was %s", err.Error())
+	}
+}
+
+func TestMultiError_Unwrap(t *testing.T) {
+	a, b := errors.New("a"), errors.New("b")
+	joined := errx.Join(a, b).(*errx.MultiError)
+
+	errs := joined.Unwrap()
+	if len(errs) != 2 || errs[0] != a || errs[1] != b {
+		t.Fatalf("expected Unwrap to return [a, b] but was %v", errs)
+	}
+}
+
+func TestMultiError_Format(t *testing.T) {
+	joined := errx.Join(errx.New("a"), errx.New("b"))
+
+	dash := fmt.Sprintf("%-v", joined)
+	if dash != "1: a\n2: b" {
+		t.Fatalf("expected '1: a\\n2: b' but was %q", dash)
+	}
+
+	full := fmt.Sprintf("%v", joined)
+	if full == "" {
+		t.Fatal("expected non-empty formatted output")
+	}
+}
@@ -0,0 +1,71 @@
+package errx
+
+import "fmt"
+
+// Option configures the caller-skip depth used by New and Wrap.
+type Option func(*options)
+
+type options struct {
+	skip int
+}
+
+// Skip returns an Option that adds n additional frames to skip when capturing
+// a stack trace, for use by helpers that wrap errx's own constructors, e.g.:
+//
+//		func SetupError(msg string) error {
+//			return errx.New(msg, errx.Skip(1))
+//		}
+func Skip(n int) Option {
+	return func(o *options) { o.skip = n }
+}
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Builder constructs errors with a fixed additional caller-skip depth, for
+// libraries that wrap errx's constructors and want the reported stack trace to
+// originate at their own caller rather than inside the wrapper. Unlike
+// AdjustCallerSkipLevel, a Builder is immutable once created and safe for
+// concurrent use.
+type Builder struct {
+	// Skip is the number of additional frames to skip when capturing a stack
+	// trace, on top of what New/Wrap/Errorf/Wrapf already skip.
+	Skip int
+}
+
+// New is equivalent to the package-level New, using b.Skip as the caller-skip
+// depth.
+//
+//go:noinline
+func (b Builder) New(message string) error {
+	return newErr(message, b.Skip)
+}
+
+// Errorf is equivalent to the package-level Errorf, using b.Skip as the
+// caller-skip depth.
+//
+//go:noinline
+func (b Builder) Errorf(format string, args ...interface{}) error {
+	return newErr(fmt.Sprintf(format, args...), b.Skip)
+}
+
+// Wrap is equivalent to the package-level Wrap, using b.Skip as the
+// caller-skip depth.
+//
+//go:noinline
+func (b Builder) Wrap(err error, message string) error {
+	return wrapErr(err, message, b.Skip)
+}
+
+// Wrapf is equivalent to the package-level Wrapf, using b.Skip as the
+// caller-skip depth.
+//
+//go:noinline
+func (b Builder) Wrapf(err error, format string, args ...interface{}) error {
+	return wrapErr(err, fmt.Sprintf(format, args...), b.Skip)
+}
@@ -3,7 +3,10 @@ package errx
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"path"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -21,6 +24,10 @@ var callerSkipLevel = minCallerSkipLevel
 //
 // For example, if errx.New was wrapped in a helper function, e.g. SetupError(args...),
 // then AdjustCallerSkipLevel should be called with a value of 1.
+//
+// Deprecated: AdjustCallerSkipLevel mutates package-global state, so concurrent
+// callers with different wrapping depths will race with each other. Use a
+// Builder instead, which captures its skip depth immutably.
 func AdjustCallerSkipLevel(amt int) {
 	newCallerSkipLevel := callerSkipLevel + amt
 	if newCallerSkipLevel >= minCallerSkipLevel {
@@ -58,23 +65,136 @@ func (s StackTrace) String() string {
 	return b.String()
 }
 
+// Format implements fmt.Formatter for StackTrace, independently of *Error's own
+// Format. "%+v" prints one frame per line, the same as String(). "%v" prints
+// the frames joined onto a single line, for contexts where a multi-line dump
+// isn't wanted.
+func (s StackTrace) Format(f fmt.State, c rune) {
+	if c != 'v' {
+		return
+	}
+	if f.Flag('+') {
+		io.WriteString(f, s.String())
+		return
+	}
+	for i, fr := range s {
+		if i > 0 {
+			io.WriteString(f, "; ")
+		}
+		fmt.Fprintf(f, "%v", frameFromStackFrame(fr))
+	}
+}
+
+// Stacktracer is implemented by errors that can report their stack trace as a
+// slice of Frame, suitable for feeding into structured loggers without parsing
+// strings.
+type Stacktracer interface {
+	Stacktrace() []Frame
+}
+
+// Stacktrace returns the stack trace captured at this point in the chain as a
+// slice of Frame, or nil if none was captured here. Note that, like the
+// StackTrace field, this only reports a trace at the level of the chain where
+// one was actually captured.
+func (e *Error) Stacktrace() []Frame {
+	if e.StackTrace == nil {
+		return nil
+	}
+	frames := make([]Frame, len(e.StackTrace))
+	for i, fr := range e.StackTrace {
+		frames[i] = frameFromStackFrame(fr)
+	}
+	return frames
+}
+
+// Frame represents a single, already-resolved stack frame. It implements
+// fmt.Formatter, following the verbs used by pkg/errors: "%s" the file's
+// base name, "%+s" the function name and full file path, "%d" the line
+// number, "%n" the function name, and "%v" equivalent to "%s:%d".
+//
+// Unlike pkg/errors' Frame, this does not carry a bare program counter that
+// gets re-resolved with runtime.FuncForPC on demand: a single pc returned by
+// runtime.Callers can represent several logical calls the compiler inlined
+// together, and FuncForPC alone only ever reports the outermost one, which
+// silently produces the wrong function/file/line for every other one of
+// them. Frame is instead built directly from the function/file/line that
+// runtime.CallersFrames already resolved in getStack, so formatting it can
+// never reintroduce that bug.
+//
+// The verb handling below is adapted from pkg/errors.
+// Copyright (c) 2015, Dave Cheney <dave@cheney.net>
+type Frame struct {
+	functionName string
+	file         string
+	line         int
+}
+
+// frameFromStackFrame builds a Frame from a StackFrame already resolved by
+// getStack, rather than from a bare pc.
+func frameFromStackFrame(sf StackFrame) Frame {
+	return Frame{functionName: sf.FunctionName, file: sf.FileName, line: sf.Line}
+}
+
+func (f Frame) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		switch {
+		case s.Flag('+'):
+			io.WriteString(s, f.functionName)
+			io.WriteString(s, "\n\t")
+			io.WriteString(s, f.file)
+		default:
+			io.WriteString(s, path.Base(f.file))
+		}
+	case 'd':
+		io.WriteString(s, strconv.Itoa(f.line))
+	case 'n':
+		io.WriteString(s, funcname(f.functionName))
+	case 'v':
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// funcname trims the package path and package name from a fully qualified
+// function name, e.g. "github.com/1gm/errx.New" becomes "New". A method keeps
+// its receiver, e.g. "github.com/1gm/errx.(*Error).Format" becomes
+// "(*Error).Format".
+func funcname(name string) string {
+	i := strings.LastIndex(name, "/")
+	name = name[i+1:]
+	i = strings.Index(name, ".")
+	return name[i+1:]
+}
+
 const (
 	sep           = "/"
 	sepLen        = len(sep)
 	maxStackDepth = 32
 )
 
-func getStack() StackTrace {
+//go:noinline
+func getStack(extraSkip int) StackTrace {
 	var st StackTrace
 
 	var pcs [maxStackDepth]uintptr
-	n := runtime.Callers(callerSkipLevel, pcs[:])
-	for _, pc := range pcs[0:n] {
-		pcFunc := runtime.FuncForPC(pc)
-		name := pcFunc.Name()
-		fileName, line := pcFunc.FileLine(pc)
-		trimmed := trimGOPATH(name, fileName)
-		st = append(st, StackFrame{FunctionName: name, FileName: fileName, TrimmedFileName: trimmed, Line: line})
+	n := runtime.Callers(callerSkipLevel+extraSkip, pcs[:])
+
+	// runtime.CallersFrames is used instead of runtime.FuncForPC on each raw pc
+	// directly: a single pc returned by runtime.Callers can represent several
+	// logical calls that the compiler inlined together, and FuncForPC alone
+	// only ever reports the outermost one. CallersFrames expands those back
+	// into the individual frames callers actually expect to see, so the
+	// reported call site doesn't silently shift when inlining decisions change.
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		trimmed := trimGOPATH(frame.Function, frame.File)
+		st = append(st, StackFrame{FunctionName: frame.Function, FileName: frame.File, TrimmedFileName: trimmed, Line: frame.Line})
+		if !more {
+			break
+		}
 	}
 	return st
 }
@@ -1,6 +1,10 @@
 package errx_test
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
 	"os"
@@ -48,7 +52,7 @@ func TestStackTrace_TopFrame(t *testing.T) {
 				FunctionName:    "github.com/1gm/errx_test.genError",
 				FileName:        stackTestFilePath,
 				TrimmedFileName: "github.com/1gm/errx/stack_test.go",
-				Line:            25,
+				Line:            29,
 			},
 		},
 		{
@@ -57,7 +61,7 @@ func TestStackTrace_TopFrame(t *testing.T) {
 				FunctionName:    "github.com/1gm/errx_test.fakeErrFunction",
 				FileName:        stackTestFilePath,
 				TrimmedFileName: "github.com/1gm/errx/stack_test.go",
-				Line:            13,
+				Line:            17,
 			},
 		},
 		{
@@ -66,7 +70,7 @@ func TestStackTrace_TopFrame(t *testing.T) {
 				FunctionName:    "github.com/1gm/errx_test.anonymousFuncGenError.func1",
 				FileName:        stackTestFilePath,
 				TrimmedFileName: "github.com/1gm/errx/stack_test.go",
-				Line:            19,
+				Line:            23,
 			},
 		},
 	}
@@ -82,3 +86,130 @@ func TestStackTrace_TopFrame(t *testing.T) {
 		}
 	}
 }
+
+func TestError_Stacktrace(t *testing.T) {
+	e := asError(errx.New("boom"))
+
+	frames := e.Stacktrace()
+	if len(frames) != len(e.StackTrace) {
+		t.Fatalf("expected %d frames but got %d", len(e.StackTrace), len(frames))
+	}
+	if name := fmt.Sprintf("%n", frames[0]); name != "TestError_Stacktrace" {
+		t.Fatalf("expected top frame func name TestError_Stacktrace but was %s", name)
+	}
+
+	if frames := asError(errx.New("", errx.Skip(0))).Stacktrace(); frames == nil {
+		t.Fatal("expected non-nil frames for an error with a captured stack trace")
+	}
+}
+
+func TestError_Stacktrace_Nil(t *testing.T) {
+	e := &errx.Error{Message: "no stack"}
+	if frames := e.Stacktrace(); frames != nil {
+		t.Fatalf("expected nil frames but got %v", frames)
+	}
+}
+
+func TestFrame_Format(t *testing.T) {
+	e := asError(errx.New("boom"))
+	f := e.Stacktrace()[0]
+
+	td := []struct {
+		format   string
+		contains string
+	}{
+		{"%s", "stack_test.go"},
+		{"%+s", "github.com/1gm/errx_test."},
+		{"%n", "TestFrame_Format"},
+	}
+
+	for _, d := range td {
+		got := fmt.Sprintf(d.format, f)
+		if !strings.Contains(got, d.contains) {
+			t.Errorf("expected %s formatted with %s to contain %q but was %q", "Frame", d.format, d.contains, got)
+		}
+	}
+
+	if got := fmt.Sprintf("%d", f); got == "" || got == "0" {
+		t.Errorf("expected %%d to format a non-zero line number but got %q", got)
+	}
+
+	if got, want := fmt.Sprintf("%v", f), fmt.Sprintf("%s:%d", f, f); got != want {
+		t.Errorf("expected %%v to equal %%s:%%d, got %q want %q", got, want)
+	}
+}
+
+// TestFrame_MatchesStackFrame_OneLevelRemoved is a regression test ensuring
+// that Frame, like StackFrame, does not collapse or corrupt the frame one
+// level removed from the top of the trace (the caller of New/Wrap, the
+// overwhelmingly common frame to format). Frame used to re-resolve a bare pc
+// with runtime.FuncForPC, which only reports the outermost of however many
+// logical calls the compiler inlined into that pc, duplicating frame 0's data
+// or producing garbage at every other index.
+func TestFrame_MatchesStackFrame_OneLevelRemoved(t *testing.T) {
+	e := asError(directNew())
+	if len(e.StackTrace) < 2 {
+		t.Fatal("expected at least 2 stack frames")
+	}
+
+	frames := e.Stacktrace()
+	want := e.StackTrace[1]
+
+	if got := fmt.Sprintf("%n", frames[1]); !strings.HasSuffix(want.FunctionName, got) {
+		t.Errorf("expected frame[1] function name to be a suffix match for %s but was %s", want.FunctionName, got)
+	}
+	if got := fmt.Sprintf("%d", frames[1]); got != strconv.Itoa(want.Line) {
+		t.Errorf("expected frame[1] line %d but was %s", want.Line, got)
+	}
+	if got := fmt.Sprintf("%n", frames[1]); got == fmt.Sprintf("%n", frames[0]) {
+		t.Errorf("expected frame[1] to differ from frame[0], both reported %s", got)
+	}
+}
+
+func directNew() error { return errx.New("direct") }
+
+func directWrap() error { return errx.Wrap(errors.New("inner"), "wrapped") }
+
+func helperNew(msg string) error {
+	return errx.New(msg, errx.Skip(1))
+}
+
+func viaSkippedHelper() error { return helperNew("via helper") }
+
+func viaBuilder() error {
+	b := errx.Builder{Skip: 0}
+	return b.New("via builder")
+}
+
+// TestStackTrace_TopFrame_ExactCallSite is a regression test for a bug where
+// adding Option/Builder support to New/Wrap shifted the reported call site by
+// a frame. It asserts the exact function and line captured, not merely that a
+// stack trace exists.
+func TestStackTrace_TopFrame_ExactCallSite(t *testing.T) {
+	var td = []struct {
+		name         string
+		e            *errx.Error
+		functionName string
+		line         int
+	}{
+		{"New", asError(directNew()), "github.com/1gm/errx_test.directNew", 169},
+		{"Wrap", asError(directWrap()), "github.com/1gm/errx_test.directWrap", 171},
+		{"Skip option", asError(viaSkippedHelper()), "github.com/1gm/errx_test.viaSkippedHelper", 177},
+		{"Builder", asError(viaBuilder()), "github.com/1gm/errx_test.viaBuilder", 181},
+	}
+
+	for _, d := range td {
+		t.Run(d.name, func(t *testing.T) {
+			if d.e.StackTrace == nil {
+				t.Fatal("expected stack trace but was nil")
+			}
+			top := d.e.StackTrace[0]
+			if top.FunctionName != d.functionName {
+				t.Errorf("expected function %s but was %s", d.functionName, top.FunctionName)
+			}
+			if top.Line != d.line {
+				t.Errorf("expected line %d but was %d", d.line, top.Line)
+			}
+		})
+	}
+}